@@ -19,7 +19,9 @@ package debugger
 import (
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -35,36 +37,120 @@ type CacheComparer struct {
 	PodLister  corelisters.PodLister
 	Cache      schedulerinternalcache.Cache
 	PodQueue   internalqueue.SchedulingQueue
+
+	// Reconciler decides what to do with the node/pod drift found by
+	// Compare. It defaults to LogOnlyReconciler, preserving the original
+	// warn-and-return behavior; set it to a RepairReconciler to self-heal.
+	Reconciler Reconciler
+
+	// DiffEvents, if set, receives the CacheDiff produced by every Compare
+	// cycle in addition to it being returned directly. The send is
+	// non-blocking: a slow or absent consumer never stalls Compare.
+	DiffEvents chan CacheDiff
+}
+
+// CacheComparerOption configures optional behavior of a CacheComparer built
+// via NewCacheComparer.
+type CacheComparerOption func(*CacheComparer)
+
+// WithReconciler overrides the default LogOnlyReconciler, e.g. with a
+// RepairReconciler to enable self-healing without a scheduler restart.
+func WithReconciler(r Reconciler) CacheComparerOption {
+	return func(c *CacheComparer) { c.Reconciler = r }
 }
 
-// Compare compares the nodes and pods of NodeLister with Cache.Snapshot.
-func (c *CacheComparer) Compare() error {
+// NewCacheComparer creates a CacheComparer that logs drift but does not
+// repair it. Pass CacheComparerOptions to customize it, e.g. WithReconciler.
+func NewCacheComparer(nodeLister corelisters.NodeLister, podLister corelisters.PodLister,
+	cache schedulerinternalcache.Cache, podQueue internalqueue.SchedulingQueue, opts ...CacheComparerOption) *CacheComparer {
+	c := &CacheComparer{
+		NodeLister: nodeLister,
+		PodLister:  podLister,
+		Cache:      cache,
+		PodQueue:   podQueue,
+		Reconciler: LogOnlyReconciler{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Compare compares the nodes and pods of NodeLister with Cache.Snapshot. It
+// returns a CacheDiff describing everything it found, and, if DiffEvents is
+// set, also emits that CacheDiff on the channel.
+func (c *CacheComparer) Compare() (CacheDiff, error) {
 	klog.V(3).Info("cache comparer started")
 	defer klog.V(3).Info("cache comparer finished")
 
+	timer := prometheus.NewTimer(compareDurationSeconds)
+	defer timer.ObserveDuration()
+
+	diff := CacheDiff{Time: time.Now()}
+
 	nodes, err := c.NodeLister.List(labels.Everything())
 	if err != nil {
-		return err
+		return diff, err
 	}
 
 	pods, err := c.PodLister.List(labels.Everything())
 	if err != nil {
-		return err
+		return diff, err
 	}
 
 	snapshot := c.Cache.Snapshot()
 
 	pendingPods := c.PodQueue.PendingPods()
 
-	if missed, redundant := c.CompareNodes(nodes, snapshot.Nodes); len(missed)+len(redundant) != 0 {
-		klog.Warningf("cache mismatch: missed nodes: %s; redundant nodes: %s", missed, redundant)
+	diff.MissedNodes, diff.RedundantNodes = c.CompareNodes(nodes, snapshot.Nodes)
+	diff.MissedPods, diff.RedundantPods, diff.Fingerprints = c.ComparePods(pods, pendingPods, snapshot.Nodes)
+	diff.MissedDevices, diff.RedundantDevices, diff.DriftedDevices = c.CompareDevices(pods, snapshot.Nodes)
+
+	missedDevicesTotal.Add(float64(len(diff.MissedDevices)))
+	redundantDevicesTotal.Add(float64(len(diff.RedundantDevices)))
+	driftedDevicesTotal.Add(float64(len(diff.DriftedDevices)))
+
+	if len(diff.MissedNodes)+len(diff.RedundantNodes)+len(diff.MissedPods)+len(diff.RedundantPods) != 0 {
+		reconciler := c.Reconciler
+		if reconciler == nil {
+			reconciler = LogOnlyReconciler{}
+		}
+
+		actualNodes := map[string]*v1.Node{}
+		for _, node := range nodes {
+			actualNodes[node.Name] = node
+		}
+
+		actualPods := map[string]*v1.Pod{}
+		for _, pod := range pods {
+			actualPods[string(pod.UID)] = pod
+		}
+
+		cachedPods := map[string]*v1.Pod{}
+		for _, nodeinfo := range snapshot.Nodes {
+			for _, pod := range nodeinfo.Pods() {
+				cachedPods[string(pod.UID)] = pod
+			}
+		}
+
+		if err := reconciler.Reconcile(diff.MissedNodes, diff.RedundantNodes, actualNodes, diff.MissedPods, diff.RedundantPods, actualPods, cachedPods); err != nil {
+			klog.Warningf("cache reconciler failed: %v", err)
+		}
 	}
 
-	if missed, redundant := c.ComparePods(pods, pendingPods, snapshot.Nodes); len(missed)+len(redundant) != 0 {
-		klog.Warningf("cache mismatch: missed pods: %s; redundant pods: %s", missed, redundant)
+	if len(diff.MissedDevices)+len(diff.RedundantDevices)+len(diff.DriftedDevices) != 0 {
+		klog.Warningf("cache mismatch: missed devices: %v; redundant devices: %v; drifted devices: %v", diff.MissedDevices, diff.RedundantDevices, diff.DriftedDevices)
 	}
 
-	return nil
+	if c.DiffEvents != nil {
+		select {
+		case c.DiffEvents <- diff:
+		default:
+			klog.V(4).Info("cache comparer: DiffEvents consumer is not keeping up, dropping diff")
+		}
+	}
+
+	return diff, nil
 }
 
 // CompareNodes compares actual nodes with cached nodes.
@@ -79,18 +165,55 @@ func (c *CacheComparer) CompareNodes(nodes []*v1.Node, nodeinfos map[string]*sch
 		cached = append(cached, nodeName)
 	}
 
-	return compareStrings(actual, cached)
+	missed, redundant = compareStrings(actual, cached)
+	missedNodesTotal.Add(float64(len(missed)))
+	redundantNodesTotal.Add(float64(len(redundant)))
+	return missed, redundant
 }
 
-// ComparePods compares actual pods with cached pods.
-func (c *CacheComparer) ComparePods(pods, waitingPods []*v1.Pod, nodeinfos map[string]*schedulernodeinfo.NodeInfo) (missed, redundant []string) {
+// ComparePods compares actual pods with cached pods. As a fast path, it first
+// computes a commutative pod-set fingerprint per node from pods and from the
+// matching NodeInfo; nodes whose fingerprints agree are skipped entirely,
+// since this means the cache's pod set for that node already matches the
+// live one. Only nodes whose fingerprints disagree pay for the full
+// sort/merge diff below.
+func (c *CacheComparer) ComparePods(pods, waitingPods []*v1.Pod, nodeinfos map[string]*schedulernodeinfo.NodeInfo) (missed, redundant []string, fingerprints map[string]string) {
+	actualByNode := map[string][]*v1.Pod{}
+	for _, pod := range pods {
+		actualByNode[pod.Spec.NodeName] = append(actualByNode[pod.Spec.NodeName], pod)
+	}
+
+	fingerprints = map[string]string{}
+	matched := map[string]bool{}
+	for nodeName, nodeinfo := range nodeinfos {
+		actualFp := NewPodFingerprint(nodeName)
+		for _, pod := range actualByNode[nodeName] {
+			actualFp.Add(string(pod.UID))
+		}
+
+		cachedFp := NewPodFingerprint(nodeName)
+		for _, pod := range nodeinfo.Pods() {
+			cachedFp.Add(string(pod.UID))
+		}
+
+		actualSig := actualFp.Sign()
+		fingerprints[nodeName] = actualSig
+		matched[nodeName] = actualSig == cachedFp.Sign()
+	}
+
 	actual := []string{}
 	for _, pod := range pods {
+		if matched[pod.Spec.NodeName] {
+			continue
+		}
 		actual = append(actual, string(pod.UID))
 	}
 
 	cached := []string{}
-	for _, nodeinfo := range nodeinfos {
+	for nodeName, nodeinfo := range nodeinfos {
+		if matched[nodeName] {
+			continue
+		}
 		for _, pod := range nodeinfo.Pods() {
 			cached = append(cached, string(pod.UID))
 		}
@@ -99,7 +222,10 @@ func (c *CacheComparer) ComparePods(pods, waitingPods []*v1.Pod, nodeinfos map[s
 		cached = append(cached, string(pod.UID))
 	}
 
-	return compareStrings(actual, cached)
+	missed, redundant = compareStrings(actual, cached)
+	missedPodsTotal.Add(float64(len(missed)))
+	redundantPodsTotal.Add(float64(len(redundant)))
+	return missed, redundant, fingerprints
 }
 
 func compareStrings(actual, cached []string) (missed, redundant []string) {
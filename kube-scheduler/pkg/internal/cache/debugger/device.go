@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"strings"
+
+	"k8s.io/api/core/v1"
+
+	schedulernodeinfo "github.com/Microsoft/KubeDevice/kube-scheduler/pkg/nodeinfo"
+)
+
+// podDeviceAnnotation records the device IDs allocated to a pod's
+// containers, as a comma-separated list, set by the KubeDevice device
+// plugin at bind time, e.g. "gpu-0,gpu-1".
+const podDeviceAnnotation = "kubedevice.microsoft.com/allocated-devices"
+
+// DeviceDiff describes a single device-accounting mismatch on a node.
+type DeviceDiff struct {
+	NodeName string
+	DeviceID string
+	// CachedBy is the UID of the pod the scheduler cache believes holds
+	// DeviceID, if any.
+	CachedBy string
+	// ActualBy is the UID of the pod that actually holds DeviceID, if any.
+	ActualBy string
+}
+
+// podDevices extracts the device IDs allocated to pod from its annotations.
+func podDevices(pod *v1.Pod) []string {
+	raw, ok := pod.Annotations[podDeviceAnnotation]
+	if !ok || raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+// CompareDevices compares actual device ownership, derived from live pods,
+// with cached device ownership in the scheduler cache's NodeInfo.
+func (c *CacheComparer) CompareDevices(pods []*v1.Pod, nodeinfos map[string]*schedulernodeinfo.NodeInfo) (missed, redundant, drifted []DeviceDiff) {
+	missed, redundant, drifted = []DeviceDiff{}, []DeviceDiff{}, []DeviceDiff{}
+
+	actualByNode := map[string]map[string]string{} // nodeName -> deviceID -> pod UID
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		for _, deviceID := range podDevices(pod) {
+			owners, ok := actualByNode[pod.Spec.NodeName]
+			if !ok {
+				owners = map[string]string{}
+				actualByNode[pod.Spec.NodeName] = owners
+			}
+			owners[deviceID] = string(pod.UID)
+		}
+	}
+
+	for nodeName, nodeinfo := range nodeinfos {
+		m, r, d := diffDeviceOwners(nodeName, actualByNode[nodeName], nodeinfo.Devices())
+		missed = append(missed, m...)
+		redundant = append(redundant, r...)
+		drifted = append(drifted, d...)
+	}
+
+	return
+}
+
+// diffDeviceOwners classifies one node's device ownership. actual and
+// cached both map deviceID -> owning pod UID.
+func diffDeviceOwners(nodeName string, actual, cached map[string]string) (missed, redundant, drifted []DeviceDiff) {
+	missed, redundant, drifted = []DeviceDiff{}, []DeviceDiff{}, []DeviceDiff{}
+
+	for deviceID, cachedBy := range cached {
+		actualBy, ok := actual[deviceID]
+		switch {
+		case !ok:
+			redundant = append(redundant, DeviceDiff{NodeName: nodeName, DeviceID: deviceID, CachedBy: cachedBy})
+		case actualBy != cachedBy:
+			drifted = append(drifted, DeviceDiff{NodeName: nodeName, DeviceID: deviceID, CachedBy: cachedBy, ActualBy: actualBy})
+		}
+	}
+
+	for deviceID, actualBy := range actual {
+		if _, ok := cached[deviceID]; !ok {
+			missed = append(missed, DeviceDiff{NodeName: nodeName, DeviceID: deviceID, ActualBy: actualBy})
+		}
+	}
+
+	return
+}
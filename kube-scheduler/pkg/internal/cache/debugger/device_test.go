@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodDevices(t *testing.T) {
+	cases := []struct {
+		name string
+		pod  *v1.Pod
+		want []string
+	}{
+		{
+			name: "no annotation",
+			pod:  &v1.Pod{},
+			want: nil,
+		},
+		{
+			name: "empty annotation",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{podDeviceAnnotation: ""}}},
+			want: nil,
+		},
+		{
+			name: "single device",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{podDeviceAnnotation: "gpu-0"}}},
+			want: []string{"gpu-0"},
+		},
+		{
+			name: "multiple devices",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{podDeviceAnnotation: "gpu-0,gpu-1"}}},
+			want: []string{"gpu-0", "gpu-1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := podDevices(tc.pod)
+			if len(got) != len(tc.want) {
+				t.Fatalf("podDevices() = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("podDevices() = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffDeviceOwnersRedundant(t *testing.T) {
+	cached := map[string]string{"gpu-0": "pod-a"}
+	actual := map[string]string{}
+
+	missed, redundant, drifted := diffDeviceOwners("node-1", actual, cached)
+
+	if len(missed) != 0 || len(drifted) != 0 {
+		t.Fatalf("expected only redundant devices, got missed=%v drifted=%v", missed, drifted)
+	}
+	if len(redundant) != 1 || redundant[0].DeviceID != "gpu-0" || redundant[0].CachedBy != "pod-a" {
+		t.Fatalf("unexpected redundant devices: %v", redundant)
+	}
+}
+
+func TestDiffDeviceOwnersMissed(t *testing.T) {
+	cached := map[string]string{}
+	actual := map[string]string{"gpu-0": "pod-a"}
+
+	missed, redundant, drifted := diffDeviceOwners("node-1", actual, cached)
+
+	if len(redundant) != 0 || len(drifted) != 0 {
+		t.Fatalf("expected only missed devices, got redundant=%v drifted=%v", redundant, drifted)
+	}
+	if len(missed) != 1 || missed[0].DeviceID != "gpu-0" || missed[0].ActualBy != "pod-a" {
+		t.Fatalf("unexpected missed devices: %v", missed)
+	}
+}
+
+func TestDiffDeviceOwnersDrifted(t *testing.T) {
+	cached := map[string]string{"gpu-0": "pod-old"}
+	actual := map[string]string{"gpu-0": "pod-new"}
+
+	missed, redundant, drifted := diffDeviceOwners("node-1", actual, cached)
+
+	if len(missed) != 0 || len(redundant) != 0 {
+		t.Fatalf("expected only drifted devices, got missed=%v redundant=%v", missed, redundant)
+	}
+	if len(drifted) != 1 || drifted[0].CachedBy != "pod-old" || drifted[0].ActualBy != "pod-new" {
+		t.Fatalf("unexpected drifted devices: %v", drifted)
+	}
+}
+
+func TestDiffDeviceOwnersAgree(t *testing.T) {
+	cached := map[string]string{"gpu-0": "pod-a"}
+	actual := map[string]string{"gpu-0": "pod-a"}
+
+	missed, redundant, drifted := diffDeviceOwners("node-1", actual, cached)
+
+	if len(missed)+len(redundant)+len(drifted) != 0 {
+		t.Fatalf("expected no diffs when actual and cached agree, got missed=%v redundant=%v drifted=%v", missed, redundant, drifted)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import "time"
+
+// CacheDiff is a structured snapshot of every mismatch CacheComparer found
+// during a single Compare cycle. It is returned from Compare and, if
+// CacheComparer.DiffEvents is set, also emitted on that channel so
+// higher-level controllers can subscribe to drift events instead of
+// scraping klog.
+type CacheDiff struct {
+	Time time.Time
+
+	MissedNodes, RedundantNodes []string
+	MissedPods, RedundantPods   []string
+
+	MissedDevices, RedundantDevices, DriftedDevices []DeviceDiff
+
+	// Fingerprints holds the pod-set fingerprint computed for each node
+	// during this cycle's ComparePods, keyed by node name, so operators can
+	// correlate mismatches across scheduler restarts without reaching into
+	// CacheComparer's internal state.
+	Fingerprints map[string]string
+}
+
+// Empty reports whether the diff found no mismatches at all.
+func (d CacheDiff) Empty() bool {
+	return len(d.MissedNodes)+len(d.RedundantNodes)+
+		len(d.MissedPods)+len(d.RedundantPods)+
+		len(d.MissedDevices)+len(d.RedundantDevices)+len(d.DriftedDevices) == 0
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// pfpVersion tags every digest so a future, incompatible fingerprint format
+// is never mistaken for a match against an older one.
+const pfpVersion = "pfp0v001"
+
+// PodFingerprint is an order-independent XOR hash over a set of pod UIDs,
+// seeded per node. XOR is commutative and associative, so Add can be called
+// in any order.
+type PodFingerprint struct {
+	accumulator [sha256.Size]byte
+}
+
+// NewPodFingerprint returns a PodFingerprint seeded for nodeName.
+func NewPodFingerprint(nodeName string) *PodFingerprint {
+	f := &PodFingerprint{}
+	f.accumulator = sha256.Sum256([]byte(nodeName))
+	return f
+}
+
+// Add folds the SHA-256 digest of identifier (typically a pod's UID, i.e.
+// the same identity compareStrings diffs on) into the accumulator.
+func (f *PodFingerprint) Add(identifier string) {
+	h := sha256.Sum256([]byte(identifier))
+	for i := range f.accumulator {
+		f.accumulator[i] ^= h[i]
+	}
+}
+
+// Sign returns the hex-encoded digest of the fingerprint, prefixed with its
+// version tag.
+func (f *PodFingerprint) Sign() string {
+	return pfpVersion + hex.EncodeToString(f.accumulator[:])
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import "testing"
+
+func TestPodFingerprintOrderIndependent(t *testing.T) {
+	a := NewPodFingerprint("node-1")
+	a.Add("uid-1")
+	a.Add("uid-2")
+	a.Add("uid-3")
+
+	b := NewPodFingerprint("node-1")
+	b.Add("uid-3")
+	b.Add("uid-1")
+	b.Add("uid-2")
+
+	if a.Sign() != b.Sign() {
+		t.Fatalf("expected order-independent signatures to match: %s != %s", a.Sign(), b.Sign())
+	}
+}
+
+func TestPodFingerprintDetectsSetDifference(t *testing.T) {
+	a := NewPodFingerprint("node-1")
+	a.Add("uid-1")
+	a.Add("uid-2")
+
+	b := NewPodFingerprint("node-1")
+	b.Add("uid-1")
+	b.Add("uid-3")
+
+	if a.Sign() == b.Sign() {
+		t.Fatalf("expected different pod sets to produce different signatures, both were %s", a.Sign())
+	}
+}
+
+// TestPodFingerprintSameNameDifferentUID guards the ghost-reservation case
+// this fingerprint exists to catch: a pod that was deleted and recreated
+// under the same namespace/name has a different UID, and the fingerprint
+// must be sensitive to that, not just to the name.
+func TestPodFingerprintSameNameDifferentUID(t *testing.T) {
+	live := NewPodFingerprint("node-1")
+	live.Add("uid-new")
+
+	cached := NewPodFingerprint("node-1")
+	cached.Add("uid-old")
+
+	if live.Sign() == cached.Sign() {
+		t.Fatalf("expected a stale UID for the same pod name to change the signature")
+	}
+}
+
+func TestPodFingerprintDifferentNodeSeed(t *testing.T) {
+	a := NewPodFingerprint("node-1")
+	b := NewPodFingerprint("node-2")
+
+	if a.Sign() == b.Sign() {
+		t.Fatalf("expected two nodes with empty pod sets to have different signatures")
+	}
+}
+
+func TestPodFingerprintSignHasVersionPrefix(t *testing.T) {
+	f := NewPodFingerprint("node-1")
+	f.Add("uid-1")
+
+	sig := f.Sign()
+	if len(sig) <= len(pfpVersion) || sig[:len(pfpVersion)] != pfpVersion {
+		t.Fatalf("expected signature %q to start with version tag %q", sig, pfpVersion)
+	}
+}
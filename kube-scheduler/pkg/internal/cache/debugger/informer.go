@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	schedulerinternalcache "github.com/Microsoft/KubeDevice/kube-scheduler/pkg/internal/cache"
+	internalqueue "github.com/Microsoft/KubeDevice/kube-scheduler/pkg/internal/queue"
+)
+
+// defaultDedicatedResyncPeriod is used when CacheComparerConfig.ResyncPeriod
+// is left zero in Dedicated mode.
+const defaultDedicatedResyncPeriod = 30 * time.Minute
+
+// InformerMode selects where a CacheComparer's NodeLister/PodLister come
+// from.
+type InformerMode int
+
+const (
+	// Shared reads from the same informers that populate the scheduler
+	// cache. This is the default and costs no extra watch connections, but
+	// can observe a partial update the cache is mid-processing.
+	Shared InformerMode = iota
+	// Dedicated reads from an independent SharedInformerFactory, at the
+	// cost of one extra watch connection, so Compare's "actual" side is a
+	// truly independent view of the API server.
+	Dedicated
+)
+
+// CacheComparerConfig selects and configures a CacheComparer's informer
+// mode.
+type CacheComparerConfig struct {
+	InformerMode InformerMode
+
+	// Client builds the dedicated SharedInformerFactory; required when
+	// InformerMode is Dedicated.
+	Client kubernetes.Interface
+	// ResyncPeriod is the resync period of the dedicated informer factory.
+	// Defaults to defaultDedicatedResyncPeriod if zero. Ignored in Shared
+	// mode.
+	ResyncPeriod time.Duration
+}
+
+// NewCacheComparerWithConfig creates a CacheComparer whose NodeLister and
+// PodLister are wired according to cfg. In Dedicated mode it starts its own
+// SharedInformerFactory and blocks until its caches have synced; in Shared
+// mode it simply wraps the listers the caller already has.
+func NewCacheComparerWithConfig(cfg CacheComparerConfig, nodeLister corelisters.NodeLister, podLister corelisters.PodLister,
+	cache schedulerinternalcache.Cache, podQueue internalqueue.SchedulingQueue, stopCh <-chan struct{}, opts ...CacheComparerOption) (*CacheComparer, error) {
+	if cfg.InformerMode != Dedicated {
+		return NewCacheComparer(nodeLister, podLister, cache, podQueue, opts...), nil
+	}
+
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("debugger: CacheComparerConfig.Client is required in Dedicated InformerMode")
+	}
+
+	resync := cfg.ResyncPeriod
+	if resync == 0 {
+		resync = defaultDedicatedResyncPeriod
+	}
+
+	factory := informers.NewSharedInformerFactory(cfg.Client, resync)
+	dedicatedNodeLister := factory.Core().V1().Nodes().Lister()
+	dedicatedPodLister := factory.Core().V1().Pods().Lister()
+
+	factory.Start(stopCh)
+	for informerType, synced := range factory.WaitForCacheSync(stopCh) {
+		if !synced {
+			return nil, fmt.Errorf("debugger: dedicated informer for %v failed to sync before stopCh closed", informerType)
+		}
+	}
+
+	return NewCacheComparer(dedicatedNodeLister, dedicatedPodLister, cache, podQueue, opts...), nil
+}
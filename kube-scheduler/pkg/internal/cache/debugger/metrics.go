@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsSubsystem = "scheduler_cache"
+
+var (
+	missedNodesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "missed_nodes_total",
+		Help:      "Number of nodes found on the API server but missing from the scheduler cache, summed across Compare cycles.",
+	})
+
+	redundantNodesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "redundant_nodes_total",
+		Help:      "Number of nodes found in the scheduler cache but missing from the API server, summed across Compare cycles.",
+	})
+
+	missedPodsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "missed_pods_total",
+		Help:      "Number of pods found on the API server but missing from the scheduler cache, summed across Compare cycles.",
+	})
+
+	redundantPodsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "redundant_pods_total",
+		Help:      "Number of pods found in the scheduler cache but missing from the API server, summed across Compare cycles.",
+	})
+
+	missedDevicesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "missed_devices_total",
+		Help:      "Number of devices found allocated to a live pod but absent from the scheduler cache, summed across Compare cycles.",
+	})
+
+	redundantDevicesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "redundant_devices_total",
+		Help:      "Number of devices found allocated in the scheduler cache but unclaimed by any live pod on that node, summed across Compare cycles.",
+	})
+
+	driftedDevicesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "drifted_devices_total",
+		Help:      "Number of devices whose recorded owner disagrees between live pods and the scheduler cache, summed across Compare cycles.",
+	})
+
+	compareDurationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "kubedevice",
+		Subsystem: metricsSubsystem,
+		Name:      "compare_duration_seconds",
+		Help:      "Duration in seconds of a complete CacheComparer.Compare cycle.",
+		Buckets:   prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		missedNodesTotal,
+		redundantNodesTotal,
+		missedPodsTotal,
+		redundantPodsTotal,
+		missedDevicesTotal,
+		redundantDevicesTotal,
+		driftedDevicesTotal,
+		compareDurationSeconds,
+	)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	schedulerinternalcache "github.com/Microsoft/KubeDevice/kube-scheduler/pkg/internal/cache"
+	internalqueue "github.com/Microsoft/KubeDevice/kube-scheduler/pkg/internal/queue"
+)
+
+const (
+	// defaultMaxRepairsPerCycle bounds how many entries RepairReconciler
+	// will touch in a single Reconcile call.
+	defaultMaxRepairsPerCycle = 50
+	// defaultMinConsecutiveDrifts is the number of consecutive Compare
+	// cycles an entry must be observed as drifted before RepairReconciler
+	// acts on it.
+	defaultMinConsecutiveDrifts = 3
+)
+
+// Reconciler reacts to the drift discovered during a Compare cycle.
+type Reconciler interface {
+	Reconcile(missedNodes, redundantNodes []string, actualNodes map[string]*v1.Node,
+		missedPods, redundantPods []string, actualPods, cachedPods map[string]*v1.Pod) error
+}
+
+// LogOnlyReconciler is the default Reconciler: it only warns about drift,
+// which is Compare's original behavior.
+type LogOnlyReconciler struct{}
+
+// Reconcile implements Reconciler.
+func (LogOnlyReconciler) Reconcile(missedNodes, redundantNodes []string, actualNodes map[string]*v1.Node,
+	missedPods, redundantPods []string, actualPods, cachedPods map[string]*v1.Pod) error {
+	if len(missedNodes)+len(redundantNodes) != 0 {
+		klog.Warningf("cache mismatch: missed nodes: %s; redundant nodes: %s", missedNodes, redundantNodes)
+	}
+	if len(missedPods)+len(redundantPods) != 0 {
+		klog.Warningf("cache mismatch: missed pods: %s; redundant pods: %s", missedPods, redundantPods)
+	}
+	return nil
+}
+
+// RepairReconciler heals drift by mutating the scheduler cache directly and
+// re-queuing affected pods, bounded by MaxRepairsPerCycle and gated by
+// MinConsecutiveDrifts so transient mismatches are not repaired.
+type RepairReconciler struct {
+	Cache    schedulerinternalcache.Cache
+	PodQueue internalqueue.SchedulingQueue
+
+	// MaxRepairsPerCycle bounds how many entries are repaired per Reconcile
+	// call. Defaults to defaultMaxRepairsPerCycle if zero.
+	MaxRepairsPerCycle int
+	// MinConsecutiveDrifts is the number of consecutive cycles an entry must
+	// be observed as drifted before it is repaired. Defaults to
+	// defaultMinConsecutiveDrifts if zero.
+	MinConsecutiveDrifts int
+
+	// drifted counts, per entry key, how many consecutive cycles it has been
+	// seen as drifted.
+	drifted map[string]int
+}
+
+// NewRepairReconciler returns a RepairReconciler with the default safety
+// budget and hysteresis window.
+func NewRepairReconciler(cache schedulerinternalcache.Cache, podQueue internalqueue.SchedulingQueue) *RepairReconciler {
+	return &RepairReconciler{
+		Cache:                cache,
+		PodQueue:             podQueue,
+		MaxRepairsPerCycle:   defaultMaxRepairsPerCycle,
+		MinConsecutiveDrifts: defaultMinConsecutiveDrifts,
+		drifted:              map[string]int{},
+	}
+}
+
+// prune drops the drift streak for any tracked key absent from the current
+// cycle's keys, so healing for even one cycle resets the streak.
+func (r *RepairReconciler) prune(keys map[string]bool) {
+	for key := range r.drifted {
+		if !keys[key] {
+			delete(r.drifted, key)
+		}
+	}
+}
+
+// observe bumps key's consecutive-drift streak and reports whether it has
+// now drifted for enough cycles to be repaired. Call it for every key seen
+// in the current cycle regardless of remaining repair budget, so one
+// category exhausting the budget doesn't stall another's streak.
+func (r *RepairReconciler) observe(key string) bool {
+	if r.drifted == nil {
+		r.drifted = map[string]int{}
+	}
+	r.drifted[key]++
+	min := r.MinConsecutiveDrifts
+	if min == 0 {
+		min = defaultMinConsecutiveDrifts
+	}
+	return r.drifted[key] >= min
+}
+
+func (r *RepairReconciler) clear(key string) {
+	delete(r.drifted, key)
+}
+
+// Reconcile implements Reconciler.
+func (r *RepairReconciler) Reconcile(missedNodes, redundantNodes []string, actualNodes map[string]*v1.Node,
+	missedPods, redundantPods []string, actualPods, cachedPods map[string]*v1.Pod) error {
+	budget := r.MaxRepairsPerCycle
+	if budget == 0 {
+		budget = defaultMaxRepairsPerCycle
+	}
+	repairs := 0
+
+	currentKeys := map[string]bool{}
+	for _, name := range missedNodes {
+		currentKeys["node/missed/"+name] = true
+	}
+	for _, name := range redundantNodes {
+		currentKeys["node/redundant/"+name] = true
+	}
+	for _, uid := range missedPods {
+		currentKeys["pod/missed/"+uid] = true
+	}
+	for _, uid := range redundantPods {
+		currentKeys["pod/redundant/"+uid] = true
+	}
+	r.prune(currentKeys)
+
+	// Observe every current-cycle key up front, independent of budget.
+	ready := map[string]bool{}
+	for key := range currentKeys {
+		ready[key] = r.observe(key)
+	}
+
+	for _, name := range missedNodes {
+		if repairs >= budget {
+			break
+		}
+		key := "node/missed/" + name
+		if !ready[key] {
+			continue
+		}
+		node, ok := actualNodes[name]
+		if !ok {
+			continue
+		}
+		if err := r.Cache.AddNode(node); err != nil {
+			klog.Warningf("cache repair: failed to add missed node %s: %v", name, err)
+			continue
+		}
+		r.clear(key)
+		repairs++
+	}
+
+	for _, name := range redundantNodes {
+		if repairs >= budget {
+			break
+		}
+		key := "node/redundant/" + name
+		if !ready[key] {
+			continue
+		}
+		if err := r.Cache.RemoveNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}); err != nil {
+			klog.Warningf("cache repair: failed to remove redundant node %s: %v", name, err)
+			continue
+		}
+		r.clear(key)
+		repairs++
+	}
+
+	podQueueDirty := false
+
+	for _, uid := range missedPods {
+		if repairs >= budget {
+			break
+		}
+		key := "pod/missed/" + uid
+		if !ready[key] {
+			continue
+		}
+		pod, ok := actualPods[uid]
+		if !ok {
+			continue
+		}
+		if err := r.Cache.AddPod(pod); err != nil {
+			klog.Warningf("cache repair: failed to add missed pod %s: %v", uid, err)
+			continue
+		}
+		r.clear(key)
+		repairs++
+		podQueueDirty = true
+	}
+
+	for _, uid := range redundantPods {
+		if repairs >= budget {
+			break
+		}
+		key := "pod/redundant/" + uid
+		if !ready[key] {
+			continue
+		}
+		pod, ok := cachedPods[uid]
+		if !ok {
+			continue
+		}
+		if err := r.Cache.RemovePod(pod); err != nil {
+			klog.Warningf("cache repair: failed to remove redundant pod %s: %v", uid, err)
+			continue
+		}
+		r.clear(key)
+		repairs++
+		podQueueDirty = true
+	}
+
+	if podQueueDirty {
+		r.PodQueue.MoveAllToActiveOrBackoffQueue("cache-repair")
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debugger
+
+import "testing"
+
+func TestRepairReconcilerObserveRequiresConsecutiveCycles(t *testing.T) {
+	r := &RepairReconciler{MinConsecutiveDrifts: 3}
+
+	for i, want := range []bool{false, false, true} {
+		got := r.observe("node/missed/n1")
+		if got != want {
+			t.Fatalf("cycle %d: observe() = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+// TestRepairReconcilerPruneResetsOnHeal is the regression test for the
+// hysteresis bug: an entry that heals for even one cycle must lose its
+// streak, since "drifted for N consecutive cycles" is not the same as
+// "drifted for N cumulative, possibly-interrupted cycles."
+func TestRepairReconcilerPruneResetsOnHeal(t *testing.T) {
+	r := &RepairReconciler{MinConsecutiveDrifts: 3}
+	key := "pod/missed/p1"
+
+	r.observe(key)
+	r.observe(key)
+	if r.drifted[key] != 2 {
+		t.Fatalf("expected streak of 2 before healing, got %d", r.drifted[key])
+	}
+
+	// The entry is absent from this cycle's key set: it healed.
+	r.prune(map[string]bool{})
+	if _, tracked := r.drifted[key]; tracked {
+		t.Fatalf("expected streak to be forgotten after healing for a cycle")
+	}
+
+	// Drifting again afterward must start a fresh streak, not resume at 2.
+	if got := r.observe(key); got {
+		t.Fatalf("expected a single post-heal observation not to already be ready")
+	}
+	if r.drifted[key] != 1 {
+		t.Fatalf("expected streak to restart at 1 after healing, got %d", r.drifted[key])
+	}
+}
+
+func TestRepairReconcilerPruneKeepsCurrentKeys(t *testing.T) {
+	r := &RepairReconciler{MinConsecutiveDrifts: 3}
+	r.observe("node/missed/n1")
+	r.observe("node/missed/n2")
+
+	r.prune(map[string]bool{"node/missed/n1": true})
+
+	if _, tracked := r.drifted["node/missed/n1"]; !tracked {
+		t.Fatalf("expected still-drifting key to survive prune")
+	}
+	if _, tracked := r.drifted["node/missed/n2"]; tracked {
+		t.Fatalf("expected healed key to be dropped by prune")
+	}
+}
+
+// TestRepairReconcilerClear checks that a repaired entry's
+// streak does not linger and re-trigger a repair on stale state.
+func TestRepairReconcilerClear(t *testing.T) {
+	r := &RepairReconciler{MinConsecutiveDrifts: 1}
+	key := "node/redundant/n1"
+
+	if !r.observe(key) {
+		t.Fatalf("expected single observation to be ready when MinConsecutiveDrifts is 1")
+	}
+	r.clear(key)
+
+	if _, tracked := r.drifted[key]; tracked {
+		t.Fatalf("expected clear() to remove the streak entirely")
+	}
+}